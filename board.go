@@ -0,0 +1,156 @@
+package main
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// front holds the live/dead state currently being rendered; back is the
+// scratch buffer the next generation is computed into. Keeping them as flat
+// []bool grids (rather than mutating per-cell alive/aliveNextTurn fields in
+// place) lets stepGeneration compute the whole next generation from a
+// consistent snapshot with no data race against the renderer.
+var (
+	front []bool
+	back  []bool
+
+	// xPrev/xNext and yPrev/yNext are precomputed wrapped-neighbor indices
+	// per row/column, so liveNeighborsAt never needs a modulo per neighbor.
+	xPrev, xNext []int
+	yPrev, yNext []int
+)
+
+// initBoard allocates the front/back buffers and the wrap-offset tables.
+// It must run once before the board is seeded or stepped.
+func initBoard() {
+	front = make([]bool, rows*columns)
+	back = make([]bool, rows*columns)
+
+	xPrev = make([]int, rows)
+	xNext = make([]int, rows)
+	for x := 0; x < rows; x++ {
+		xPrev[x] = (x - 1 + rows) % rows
+		xNext[x] = (x + 1) % rows
+	}
+
+	yPrev = make([]int, columns)
+	yNext = make([]int, columns)
+	for y := 0; y < columns; y++ {
+		yPrev[y] = (y - 1 + columns) % columns
+		yNext[y] = (y + 1) % columns
+	}
+}
+
+// idx maps a (x, y) cell index to its position in the flat front/back grids.
+func idx(x, y int) int {
+	return x*columns + y
+}
+
+// boardAlive reports whether the cell at (x, y) is alive in the
+// currently-rendered generation.
+func boardAlive(x, y int) bool {
+	return front[idx(x, y)]
+}
+
+// setBoardAlive sets the cell at (x, y) directly in the rendered
+// generation. Used by editing (painting, pattern stamping) while the
+// simulation is paused.
+func setBoardAlive(x, y int, alive bool) {
+	front[idx(x, y)] = alive
+}
+
+// clearBoard sets every cell dead.
+func clearBoard() {
+	for i := range front {
+		front[i] = false
+	}
+}
+
+// seedBoard reseeds every cell with the same random distribution used at
+// startup.
+func seedBoard() {
+	for i := range front {
+		front[i] = rand.Float64() < chanceToLive
+	}
+}
+
+// liveNeighborsAt counts the live 8-neighbors of (x, y) in front, wrapping
+// on the torus via the precomputed xPrev/xNext/yPrev/yNext tables.
+func liveNeighborsAt(x, y int) int {
+	xp, xn := xPrev[x], xNext[x]
+	yp, yn := yPrev[y], yNext[y]
+
+	count := 0
+	if front[idx(xp, y)] {
+		count++
+	}
+	if front[idx(xn, y)] {
+		count++
+	}
+	if front[idx(x, yp)] {
+		count++
+	}
+	if front[idx(x, yn)] {
+		count++
+	}
+	if front[idx(xp, yp)] {
+		count++
+	}
+	if front[idx(xp, yn)] {
+		count++
+	}
+	if front[idx(xn, yp)] {
+		count++
+	}
+	if front[idx(xn, yn)] {
+		count++
+	}
+	return count
+}
+
+// stepGeneration computes the next generation into back by sharding rows
+// across runtime.NumCPU() worker goroutines synchronized with a
+// sync.WaitGroup, then swaps front and back so front always holds the
+// generation currently being rendered.
+func stepGeneration() {
+	workers := runtime.NumCPU()
+	if workers > rows {
+		workers = rows
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	rowsPerWorker := (rows + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * rowsPerWorker
+		end := start + rowsPerWorker
+		if end > rows {
+			end = rows
+		}
+		if start >= end {
+			continue
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for x := start; x < end; x++ {
+				for y := 0; y < columns; y++ {
+					i := idx(x, y)
+					liveCount := liveNeighborsAt(x, y)
+					if front[i] {
+						back[i] = rule.survives(liveCount)
+					} else {
+						back[i] = rule.born(liveCount)
+					}
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	front, back = back, front
+}
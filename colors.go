@@ -0,0 +1,26 @@
+package main
+
+// maxAgeForColor is the age (in generations) at which a live cell reaches
+// its oldest color under colormode=age; older cells don't get any darker.
+const maxAgeForColor = 60
+
+// ageColor maps a cell's age to a color running from bright green (young)
+// to deep blue (old), so stable long-lived structures stand out from
+// freshly-born or chaotic cells.
+func ageColor(age int) (r, g, b float32) {
+	t := float64(age) / maxAgeForColor
+	if t > 1 {
+		t = 1
+	}
+
+	young := [3]float64{0.4, 1.0, 0.2}
+	old := [3]float64{0.1, 0.1, 0.8}
+
+	return float32(lerp(young[0], old[0], t)),
+		float32(lerp(young[1], old[1], t)),
+		float32(lerp(young[2], old[2], t))
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
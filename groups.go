@@ -0,0 +1,112 @@
+package main
+
+import "math"
+
+// connectivity selects which neighbors flood-fill traverses when grouping
+// live cells into connected components.
+type connectivity int
+
+const (
+	connectivity4 connectivity = iota
+	connectivity8
+)
+
+// labelGroups labels each live cell in front with the id of its connected
+// component, wrapping on the torus, using iterative DFS (a LIFO stack) from
+// each unlabeled live cell. Dead cells get label -1. It returns the number
+// of groups found and a labels[x][y] grid matching the board's dimensions.
+func labelGroups(conn connectivity) (numGroups int, labels [][]int) {
+	labels = make([][]int, rows)
+	for x := range labels {
+		labels[x] = make([]int, columns)
+		for y := range labels[x] {
+			labels[x][y] = -1
+		}
+	}
+
+	var queue []int // flat indices, used as a LIFO stack and reused across DFS calls
+	for x := 0; x < rows; x++ {
+		for y := 0; y < columns; y++ {
+			if !boardAlive(x, y) || labels[x][y] != -1 {
+				continue
+			}
+
+			id := numGroups
+			numGroups++
+
+			queue = queue[:0]
+			queue = append(queue, idx(x, y))
+			labels[x][y] = id
+
+			for len(queue) > 0 {
+				i := queue[len(queue)-1]
+				queue = queue[:len(queue)-1]
+
+				cx, cy := i/columns, i%columns
+				for _, n := range neighborsOf(cx, cy, conn) {
+					nx, ny := n[0], n[1]
+					if boardAlive(nx, ny) && labels[nx][ny] == -1 {
+						labels[nx][ny] = id
+						queue = append(queue, idx(nx, ny))
+					}
+				}
+			}
+		}
+	}
+
+	return numGroups, labels
+}
+
+// neighborsOf returns the wrapped 4- or 8-neighbor coordinates of (x, y).
+func neighborsOf(x, y int, conn connectivity) [][2]int {
+	xp, xn := xPrev[x], xNext[x]
+	yp, yn := yPrev[y], yNext[y]
+
+	neighbors := [][2]int{
+		{xp, y}, {xn, y}, {x, yp}, {x, yn},
+	}
+	if conn == connectivity8 {
+		neighbors = append(neighbors,
+			[2]int{xp, yp}, [2]int{xp, yn}, [2]int{xn, yp}, [2]int{xn, yn},
+		)
+	}
+	return neighbors
+}
+
+// goldenRatioConjugate spaces successive hues as far apart as possible so
+// that neighboring group ids get visually distinct colors.
+const goldenRatioConjugate = 0.618033988749895
+
+// groupColor derives an RGB color for group id by walking the hue wheel in
+// golden-ratio steps, at fixed saturation and value.
+func groupColor(id int) (r, g, b float32) {
+	hue := math.Mod(float64(id)*goldenRatioConjugate, 1)
+	return hsvToRGB(hue, 0.65, 0.95)
+}
+
+// hsvToRGB converts HSV (each in [0, 1]) to RGB.
+func hsvToRGB(h, s, v float64) (r, g, b float32) {
+	i := math.Floor(h * 6)
+	f := h*6 - i
+	p := v * (1 - s)
+	q := v * (1 - f*s)
+	t := v * (1 - (1-f)*s)
+
+	var rf, gf, bf float64
+	switch int(i) % 6 {
+	case 0:
+		rf, gf, bf = v, t, p
+	case 1:
+		rf, gf, bf = q, v, p
+	case 2:
+		rf, gf, bf = p, v, t
+	case 3:
+		rf, gf, bf = p, q, v
+	case 4:
+		rf, gf, bf = t, p, v
+	case 5:
+		rf, gf, bf = v, p, q
+	}
+
+	return float32(rf), float32(gf), float32(bf)
+}
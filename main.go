@@ -1,9 +1,11 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
+	"log"
 	"math/rand"
 	"runtime"
 	"strings"
@@ -22,15 +24,20 @@ const (
 ` + "\x00"
 	fragmentShaderSource = `
     #version 410
+    uniform vec4 cellColor;
     out vec4 frag_colour;
     void main() {
-        frag_colour = vec4(0.5, 0.7, 0.1, 1);
+        frag_colour = cellColor;
     }
 ` + "\x00"
 	rows         = 200
 	columns      = 200
 	chanceToLive = 0.12
 	fps          = 60
+
+	plainR float32 = 0.5
+	plainG float32 = 0.7
+	plainB float32 = 0.1
 )
 
 var (
@@ -43,30 +50,70 @@ var (
 		0.5, 0.5, 0,
 		0.5, -0.5, 0,
 	}
-	mainWindow *glfw.Window
-	cells      [][]*cell
-	program    uint32
+	mainWindow   *glfw.Window
+	cells        [][]*cell
+	program      uint32
+	cellColorLoc int32
+	sim          = simState{running: false}
+	mouseDown    = map[glfw.MouseButton]bool{}
+
+	patternFile = flag.String("pattern", "", "path to an RLE or Plaintext (.cells) pattern file to load at startup, instead of a random board")
+	ruleString  = flag.String("rule", "B3/S23", "life-like rule in B/S notation, e.g. B3/S23 (Conway), B36/S23 (HighLife), B2/S (Seeds)")
+	colorMode   = flag.String("colormode", "plain", "cell color mode: plain, age, or group")
+	trailFrames = flag.Int("trail", 0, "render recently-dead cells as fading ghosts for N generations (0 disables)")
+	rule        = defaultRule
+	groupLabels [][]int
 )
 
 type cell struct {
-	drawable      uint32
-	alive         bool
-	aliveNextTurn bool
-	x             int
-	y             int
+	drawable uint32
+	x        int
+	y        int
+	age      int
+	deadFor  int
+}
+
+// simState tracks whether the simulation is freely running, or paused so the
+// user can edit the board, and whether a single-generation step was requested
+// while paused.
+type simState struct {
+	running    bool
+	stepOnce   bool
+	generation int
 }
 
 func main() {
+	flag.Parse()
 	runtime.LockOSThread()
 
+	parsedRule, err := ParseRule(*ruleString)
+	if err != nil {
+		log.Fatalf("parsing -rule: %v", err)
+	}
+	rule = parsedRule
+
 	mainWindow = glfwInit()
 	defer glfw.Terminate()
 
-	//mainWindow.SetMouseButtonCallback(mouseClick)
+	mainWindow.SetMouseButtonCallback(mouseClick)
+	mainWindow.SetCursorPosCallback(cursorMove)
+	mainWindow.SetKeyCallback(keyPress)
 
 	program = initOpenGL()
+	initBoard()
 	cells = createCells()
 
+	if *patternFile != "" {
+		pattern, err := loadPatternFile(*patternFile)
+		if err != nil {
+			log.Fatalf("loading pattern %q: %v", *patternFile, err)
+		}
+		clearBoard()
+		pattern.StampInto(cells, 0, 0)
+	} else {
+		seedBoard()
+	}
+
 	for !mainWindow.ShouldClose() {
 		t := time.Now()
 		draw()
@@ -74,9 +121,101 @@ func main() {
 	}
 }
 
-//func mouseClick(window *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
-//TODO: make an init state where you pick which cells should be alive by clicking on them
-//}
+// mouseClick sets the cell under the cursor: left click sets it alive,
+// right click sets it dead. A left click on a live cell leaves it alive; a
+// click does not flip a cell's state. Held buttons are tracked in mouseDown so
+// cursorMove can keep painting while the user drags.
+func mouseClick(window *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+	mouseDown[button] = action == glfw.Press
+
+	if action != glfw.Press {
+		return
+	}
+
+	xpos, ypos := window.GetCursorPos()
+	paintAt(xpos, ypos, button)
+}
+
+// cursorMove paints cells while a mouse button is held down, letting the
+// user drag out a shape instead of clicking one cell at a time.
+func cursorMove(window *glfw.Window, xpos float64, ypos float64) {
+	for button, down := range mouseDown {
+		if down {
+			paintAt(xpos, ypos, button)
+		}
+	}
+}
+
+// paintAt converts a cursor position to a cell and sets it alive (left
+// button) or dead (right button). Painting is only meaningful while the
+// simulation is paused; the resulting state takes effect on the next step.
+func paintAt(xpos float64, ypos float64, button glfw.MouseButton) {
+	if sim.running {
+		return
+	}
+
+	x, y, ok := cellAt(xpos, ypos)
+	if !ok {
+		return
+	}
+
+	switch button {
+	case glfw.MouseButtonLeft:
+		setBoardAlive(x, y, true)
+	case glfw.MouseButtonRight:
+		setBoardAlive(x, y, false)
+	}
+}
+
+// cellAt converts a cursor position in window coordinates to a (x, y) cell
+// index, inverting the same rows/columns transform createCell uses to place
+// cells in NDC space. ok is false if the cursor is outside the window.
+func cellAt(xpos float64, ypos float64) (x int, y int, ok bool) {
+	width, height := mainWindow.GetSize()
+	if xpos < 0 || ypos < 0 || xpos >= float64(width) || ypos >= float64(height) {
+		return 0, 0, false
+	}
+
+	x = int(xpos / float64(width) * float64(columns))
+	y = int((1 - ypos/float64(height)) * float64(rows))
+
+	if x < 0 || x >= len(cells) || y < 0 || y >= len(cells[0]) {
+		return 0, 0, false
+	}
+
+	return x, y, true
+}
+
+// keyPress handles the editor hotkeys: Space toggles run/pause, N advances a
+// single generation while paused, R clears the board, and C reseeds it with
+// random noise.
+func keyPress(window *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	if action != glfw.Press {
+		return
+	}
+
+	switch key {
+	case glfw.KeySpace:
+		sim.running = !sim.running
+	case glfw.KeyN:
+		if !sim.running {
+			sim.stepOnce = true
+		}
+	case glfw.KeyR:
+		sim.running = false
+		clearBoard()
+	case glfw.KeyC:
+		sim.running = false
+		seedBoard()
+	case glfw.KeyS:
+		if err := savePatternFile(fmt.Sprintf("generation-%d.rle", sim.generation)); err != nil {
+			log.Printf("saving pattern: %v", err)
+		}
+	case glfw.KeyG:
+		numGroups, _ := labelGroups(connectivity8)
+		log.Printf("generation %d: %d live groups", sim.generation, numGroups)
+	}
+}
 
 func glfwInit() *glfw.Window {
 	err := glfw.Init()
@@ -99,6 +238,9 @@ func initOpenGL() uint32 {
 		panic(any(err))
 	}
 
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+
 	vertexShader, err := compileShader(vertexShaderSource, gl.VERTEX_SHADER)
 	if err != nil {
 		panic(any(err))
@@ -112,19 +254,33 @@ func initOpenGL() uint32 {
 	gl.AttachShader(program, vertexShader)
 	gl.AttachShader(program, fragmentShader)
 	gl.LinkProgram(program)
+
+	cellColorLoc = gl.GetUniformLocation(program, gl.Str("cellColor\x00"))
+
 	return program
 }
 
 func draw() {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.UseProgram(program)
-	go func() {
-		for i := range cells {
-			for _, c := range cells[i] {
-				c.checkState(cells)
-			}
+
+	stepped := false
+	if sim.running || sim.stepOnce {
+		stepGeneration()
+		updateCellAges()
+		sim.stepOnce = false
+		sim.generation++
+		stepped = true
+	}
+
+	if *colorMode == "group" && (stepped || groupLabels == nil) {
+		numGroups, labels := labelGroups(connectivity8)
+		groupLabels = labels
+		if stepped {
+			log.Printf("generation %d: %d live groups", sim.generation, numGroups)
 		}
-	}()
+	}
+
 	for i := range cells {
 		for _, c := range cells[i] {
 			c.draw()
@@ -180,11 +336,7 @@ func createCells() [][]*cell {
 	cells := make([][]*cell, rows, rows)
 	for x := 0; x < rows; x++ {
 		for y := 0; y < columns; y++ {
-			c := createCell(x, y)
-			c.alive = rand.Float64() < chanceToLive
-			c.aliveNextTurn = c.alive
-
-			cells[x] = append(cells[x], c)
+			cells[x] = append(cells[x], createCell(x, y))
 		}
 	}
 
@@ -224,64 +376,67 @@ func createCell(x, y int) *cell {
 }
 
 func (c *cell) draw() {
-	if !c.alive {
+	alive := boardAlive(c.x, c.y)
+	if !alive && (*trailFrames <= 0 || c.deadFor == 0 || c.deadFor > *trailFrames) {
 		return
 	}
+
+	r, g, b, a := c.renderColor(alive)
+	gl.Uniform4f(cellColorLoc, r, g, b, a)
+
 	gl.BindVertexArray(c.drawable)
 	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(square)/3))
 }
 
-func (c *cell) checkState(cells [][]*cell) {
-	c.alive = c.aliveNextTurn
-	c.aliveNextTurn = c.alive
-
-	liveCount := c.liveNeighbors(cells)
-	if c.alive {
-		if liveCount < 2 {
-			c.aliveNextTurn = false
-		}
-
-		if liveCount == 2 || liveCount == 3 {
-			c.aliveNextTurn = true
-		}
+// renderColor picks the color and alpha to draw c with: a fading ghost if
+// it died within the trail window, otherwise the color for the active
+// -colormode.
+func (c *cell) renderColor(alive bool) (r, g, b, a float32) {
+	if !alive {
+		fade := 1 - float32(c.deadFor)/float32(*trailFrames+1)
+		return plainR * 0.4, plainG * 0.4, plainB * 0.4, fade * 0.6
+	}
 
-		if liveCount > 3 {
-			c.aliveNextTurn = false
-		}
-	} else {
-		if liveCount == 3 {
-			c.aliveNextTurn = true
+	switch *colorMode {
+	case "group":
+		if groupLabels != nil {
+			if id := groupLabels[c.x][c.y]; id != -1 {
+				r, g, b = groupColor(id)
+				return r, g, b, 1
+			}
 		}
+		return plainR, plainG, plainB, 1
+	case "age":
+		r, g, b = ageColor(c.age)
+		return r, g, b, 1
+	default:
+		return plainR, plainG, plainB, 1
 	}
 }
 
-func (c *cell) liveNeighbors(cells [][]*cell) int {
-	var liveNeighbors int
-	count := func(x, y int) {
-		if x == len(cells) {
-			x = 0
-		} else if x == -1 {
-			x = len(cells) - 1
-		}
-		if y == len(cells[x]) {
-			y = 0
-		} else if y == -1 {
-			y = len(cells[x]) - 1
-		}
-
-		if cells[x][y].alive {
-			liveNeighbors++
+// updateCellAges advances each cell's age/deadFor counters after a
+// generation step. back holds the generation that was just replaced (front
+// and back are swapped by stepGeneration), so comparing it against the new
+// front tells us whether a cell was just born, stayed alive, just died, or
+// has been dead for a while.
+func updateCellAges() {
+	for i := range cells {
+		for _, c := range cells[i] {
+			aliveNow := boardAlive(c.x, c.y)
+			wasAlive := back[idx(c.x, c.y)]
+
+			switch {
+			case aliveNow && wasAlive:
+				c.age++
+			case aliveNow:
+				c.age = 1
+				c.deadFor = 0
+			case wasAlive:
+				c.age = 0
+				c.deadFor = 1
+			case c.deadFor > 0:
+				c.deadFor++
+			}
 		}
 	}
-
-	count(c.x-1, c.y)
-	count(c.x+1, c.y)
-	count(c.x, c.y+1)
-	count(c.x, c.y-1)
-	count(c.x-1, c.y+1)
-	count(c.x+1, c.y+1)
-	count(c.x-1, c.y-1)
-	count(c.x+1, c.y-1)
-
-	return liveNeighbors
 }
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// rleScannerBufSize is the max token (line) size LoadRLE will accept.
+// Real-world RLE files commonly put an entire pattern body on one
+// unwrapped line, well past bufio.Scanner's 64KB default.
+const rleScannerBufSize = 10 * 1024 * 1024
+
+// Pattern is a rectangular block of live/dead cells loaded from an RLE or
+// Plaintext Life file, ready to be stamped onto the board.
+type Pattern struct {
+	width  int
+	height int
+	// alive[y][x] is true if that cell within the pattern is alive.
+	alive [][]bool
+}
+
+// LoadRLE parses the standard Life RLE format: a header line
+// "x = W, y = H, rule = B3/S23" (the rule clause is optional and ignored
+// here, since rules are handled separately by ParseRule) followed by a body
+// made of run-length-prefixed 'b' (dead), 'o' (alive) and '$' (end of row)
+// tokens, terminated by '!'.
+func LoadRLE(r io.Reader) (*Pattern, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, rleScannerBufSize), rleScannerBufSize)
+
+	var width, height int
+	var header string
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		header = line
+		break
+	}
+	if header == "" {
+		return nil, fmt.Errorf("rle: missing header line")
+	}
+
+	for _, field := range strings.Split(header, ",") {
+		field = strings.TrimSpace(field)
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		switch key {
+		case "x":
+			width, _ = strconv.Atoi(value)
+		case "y":
+			height, _ = strconv.Atoi(value)
+		}
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("rle: invalid dimensions x=%d y=%d", width, height)
+	}
+
+	alive := make([][]bool, height)
+	for y := range alive {
+		alive[y] = make([]bool, width)
+	}
+
+	x, y := 0, 0
+	count := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, r := range line {
+			switch {
+			case r >= '0' && r <= '9':
+				count = count*10 + int(r-'0')
+			case r == 'b' || r == 'o':
+				n := count
+				if n == 0 {
+					n = 1
+				}
+				for i := 0; i < n; i++ {
+					if y < height && x < width {
+						alive[y][x] = r == 'o'
+					}
+					x++
+				}
+				count = 0
+			case r == '$':
+				n := count
+				if n == 0 {
+					n = 1
+				}
+				y += n
+				x = 0
+				count = 0
+			case r == '!':
+				return &Pattern{width: width, height: height, alive: alive}, nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Pattern{width: width, height: height, alive: alive}, nil
+}
+
+// LoadPlaintext parses the Plaintext (.cells) format: lines starting with
+// '!' are comments, '.' is a dead cell and 'O' is a live cell. Every non
+// comment line contributes one row; the pattern width is the length of the
+// longest row.
+func LoadPlaintext(r io.Reader) (*Pattern, error) {
+	scanner := bufio.NewScanner(r)
+
+	var rows [][]bool
+	width := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		row := make([]bool, len(line))
+		for i, r := range line {
+			row[i] = r == 'O'
+		}
+		rows = append(rows, row)
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	alive := make([][]bool, len(rows))
+	for y, row := range rows {
+		alive[y] = make([]bool, width)
+		copy(alive[y], row)
+	}
+
+	return &Pattern{width: width, height: len(rows), alive: alive}, nil
+}
+
+// SaveRLE writes p to w in the standard Life RLE format, tagging the header
+// with r so the file records the rule the pattern was captured under.
+// LoadRLE does not read the rule clause back.
+func SaveRLE(w io.Writer, p *Pattern, r Rule) error {
+	if _, err := fmt.Fprintf(w, "x = %d, y = %d, rule = %s\n", p.width, p.height, r); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	for y := 0; y < p.height; y++ {
+		x := 0
+		for x < p.width {
+			run := 1
+			for x+run < p.width && p.alive[y][x+run] == p.alive[y][x] {
+				run++
+			}
+
+			token := byte('b')
+			if p.alive[y][x] {
+				token = 'o'
+			}
+			if run > 1 {
+				fmt.Fprintf(&b, "%d%c", run, token)
+			} else {
+				fmt.Fprintf(&b, "%c", token)
+			}
+
+			x += run
+		}
+		if y < p.height-1 {
+			b.WriteString("$")
+		}
+	}
+	b.WriteString("!")
+
+	_, err := fmt.Fprintln(w, b.String())
+	return err
+}
+
+// SavePlaintext writes p to w in the Plaintext (.cells) format.
+func SavePlaintext(w io.Writer, p *Pattern) error {
+	for y := 0; y < p.height; y++ {
+		row := make([]byte, p.width)
+		for x := 0; x < p.width; x++ {
+			if p.alive[y][x] {
+				row[x] = 'O'
+			} else {
+				row[x] = '.'
+			}
+		}
+		if _, err := fmt.Fprintln(w, string(row)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StampInto places p onto the board with its top-left corner at (ox, oy),
+// wrapping around the torus for patterns that run off the edge of the
+// board. Cells outside the pattern's footprint are left untouched. The
+// cells argument is only used to size the board; it is otherwise unused
+// since board state now lives in the front/back grids.
+func (p *Pattern) StampInto(cells [][]*cell, ox, oy int) {
+	rows := len(cells)
+	if rows == 0 {
+		return
+	}
+	columns := len(cells[0])
+
+	for y := 0; y < p.height; y++ {
+		for x := 0; x < p.width; x++ {
+			cx := ((ox+x)%rows + rows) % rows
+			cy := ((oy+y)%columns + columns) % columns
+
+			setBoardAlive(cx, cy, p.alive[y][x])
+		}
+	}
+}
+
+// loadPatternFile loads a pattern from disk, dispatching on file extension:
+// ".cells" is treated as Plaintext, anything else (including ".rle") as RLE.
+func loadPatternFile(path string) (*Pattern, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.HasSuffix(strings.ToLower(path), ".cells") {
+		return LoadPlaintext(f)
+	}
+	return LoadRLE(f)
+}
+
+// savePatternFile dumps the current board to path in RLE format.
+func savePatternFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return SaveRLE(f, boardToPattern(), rule)
+}
+
+// boardToPattern captures the current alive/dead state of the board as a
+// Pattern covering the whole grid, suitable for saving.
+func boardToPattern() *Pattern {
+	alive := make([][]bool, columns)
+	for y := range alive {
+		alive[y] = make([]bool, rows)
+	}
+
+	for x := 0; x < rows; x++ {
+		for y := 0; y < columns; y++ {
+			alive[y][x] = boardAlive(x, y)
+		}
+	}
+
+	return &Pattern{width: rows, height: columns, alive: alive}
+}
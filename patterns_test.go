@@ -0,0 +1,142 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadRLE(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        string
+		wantWidth int
+		wantHeigh int
+		wantAlive [][]bool // wantAlive[y][x]
+	}{
+		{
+			name:      "glider",
+			in:        "x = 3, y = 3, rule = B3/S23\nbob$2bo$3o!\n",
+			wantWidth: 3,
+			wantHeigh: 3,
+			wantAlive: [][]bool{
+				{false, true, false},
+				{false, false, true},
+				{true, true, true},
+			},
+		},
+		{
+			name:      "run counts split across multiple lines",
+			in:        "x = 4, y = 1, rule = B3/S23\n2b\n2o!\n",
+			wantWidth: 4,
+			wantHeigh: 1,
+			wantAlive: [][]bool{
+				{false, false, true, true},
+			},
+		},
+		{
+			name:      "comment line before header is skipped",
+			in:        "#C a comment\nx = 2, y = 1, rule = B3/S23\n2o!\n",
+			wantWidth: 2,
+			wantHeigh: 1,
+			wantAlive: [][]bool{
+				{true, true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := LoadRLE(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("LoadRLE returned error: %v", err)
+			}
+			if p.width != tt.wantWidth || p.height != tt.wantHeigh {
+				t.Fatalf("LoadRLE dimensions = %dx%d, want %dx%d", p.width, p.height, tt.wantWidth, tt.wantHeigh)
+			}
+			for y, row := range tt.wantAlive {
+				for x, want := range row {
+					if got := p.alive[y][x]; got != want {
+						t.Errorf("alive[%d][%d] = %v, want %v", y, x, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLoadRLEErrors(t *testing.T) {
+	tests := []string{
+		"",                                   // empty file, no header
+		"bob$2bo$3o!\n",                      // missing header entirely
+		"x = 0, y = 3, rule = B3/S23\n3o!\n", // invalid dimensions
+	}
+
+	for _, in := range tests {
+		t.Run(in, func(t *testing.T) {
+			if _, err := LoadRLE(strings.NewReader(in)); err == nil {
+				t.Errorf("LoadRLE(%q) = nil error, want error", in)
+			}
+		})
+	}
+}
+
+func TestLoadRLELongLine(t *testing.T) {
+	// A single unwrapped body line longer than bufio.Scanner's 64KB
+	// default token size must still load without hitting bufio.ErrTooLong.
+	const width = 100000
+	in := "x = " + "100000" + ", y = 1, rule = B3/S23\n" + strings.Repeat("o", width) + "!\n"
+
+	p, err := LoadRLE(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("LoadRLE returned error on long line: %v", err)
+	}
+	if p.width != width {
+		t.Fatalf("LoadRLE width = %d, want %d", p.width, width)
+	}
+	for x := 0; x < width; x++ {
+		if !p.alive[0][x] {
+			t.Fatalf("alive[0][%d] = false, want true", x)
+		}
+	}
+}
+
+func TestLoadPlaintext(t *testing.T) {
+	in := "!Name: glider\n!comment\n.O.\n..O\nOOO\n"
+
+	p, err := LoadPlaintext(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("LoadPlaintext returned error: %v", err)
+	}
+	if p.width != 3 || p.height != 3 {
+		t.Fatalf("LoadPlaintext dimensions = %dx%d, want 3x3", p.width, p.height)
+	}
+
+	want := [][]bool{
+		{false, true, false},
+		{false, false, true},
+		{true, true, true},
+	}
+	for y, row := range want {
+		for x, wantAlive := range row {
+			if got := p.alive[y][x]; got != wantAlive {
+				t.Errorf("alive[%d][%d] = %v, want %v", y, x, got, wantAlive)
+			}
+		}
+	}
+}
+
+func TestLoadPlaintextRaggedRows(t *testing.T) {
+	// Shorter rows are padded with dead cells out to the widest row.
+	in := ".O\nOOO\n"
+
+	p, err := LoadPlaintext(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("LoadPlaintext returned error: %v", err)
+	}
+	if p.width != 3 || p.height != 2 {
+		t.Fatalf("LoadPlaintext dimensions = %dx%d, want 3x2", p.width, p.height)
+	}
+	if p.alive[0][2] {
+		t.Errorf("alive[0][2] = true, want false (padded cell)")
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rule is an outer-totalistic life-like rule expressed as two bitmasks over
+// neighbor counts 0-8: bit n of birth is set if a dead cell with n live
+// neighbors is born, and bit n of survive is set if a live cell with n live
+// neighbors survives.
+type Rule struct {
+	birth   uint16
+	survive uint16
+}
+
+// defaultRule is Conway's Game of Life, B3/S23.
+var defaultRule = Rule{birth: 1 << 3, survive: 1<<2 | 1<<3}
+
+// ParseRule parses a B/S rulestring such as "B3/S23" (Conway), "B36/S23"
+// (HighLife), "B2/S" (Seeds) or "B678/S345678" (Day & Night) into a Rule.
+func ParseRule(s string) (Rule, error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return Rule{}, fmt.Errorf("rule %q: expected B.../S... form", s)
+	}
+
+	birthPart := strings.TrimSpace(parts[0])
+	survivePart := strings.TrimSpace(parts[1])
+
+	if !strings.HasPrefix(birthPart, "B") && !strings.HasPrefix(birthPart, "b") {
+		return Rule{}, fmt.Errorf("rule %q: birth clause must start with B", s)
+	}
+	if !strings.HasPrefix(survivePart, "S") && !strings.HasPrefix(survivePart, "s") {
+		return Rule{}, fmt.Errorf("rule %q: survive clause must start with S", s)
+	}
+
+	birth, err := parseCounts(birthPart[1:])
+	if err != nil {
+		return Rule{}, fmt.Errorf("rule %q: %v", s, err)
+	}
+	survive, err := parseCounts(survivePart[1:])
+	if err != nil {
+		return Rule{}, fmt.Errorf("rule %q: %v", s, err)
+	}
+
+	return Rule{birth: birth, survive: survive}, nil
+}
+
+// parseCounts turns a string of digits (each in 0-8) into a bitmask with
+// one bit set per digit.
+func parseCounts(digits string) (uint16, error) {
+	var mask uint16
+	for _, r := range digits {
+		n, err := strconv.Atoi(string(r))
+		if err != nil || n < 0 || n > 8 {
+			return 0, fmt.Errorf("invalid neighbor count %q", string(r))
+		}
+		mask |= 1 << uint(n)
+	}
+	return mask, nil
+}
+
+// String renders the rule back in B/S notation.
+func (r Rule) String() string {
+	return fmt.Sprintf("B%s/S%s", countsString(r.birth), countsString(r.survive))
+}
+
+func countsString(mask uint16) string {
+	var b strings.Builder
+	for n := 0; n <= 8; n++ {
+		if mask&(1<<uint(n)) != 0 {
+			fmt.Fprintf(&b, "%d", n)
+		}
+	}
+	return b.String()
+}
+
+// born reports whether a dead cell with liveCount live neighbors is born
+// under r.
+func (r Rule) born(liveCount int) bool {
+	return r.birth&(1<<uint(liveCount)) != 0
+}
+
+// survives reports whether a live cell with liveCount live neighbors stays
+// alive under r.
+func (r Rule) survives(liveCount int) bool {
+	return r.survive&(1<<uint(liveCount)) != 0
+}
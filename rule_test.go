@@ -0,0 +1,92 @@
+package main
+
+import "testing"
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          string
+		wantBirth   uint16
+		wantSurvive uint16
+	}{
+		{"conway", "B3/S23", 1 << 3, 1<<2 | 1<<3},
+		{"highlife", "B36/S23", 1<<3 | 1<<6, 1<<2 | 1<<3},
+		{"seeds, empty survive clause", "B2/S", 1 << 2, 0},
+		{"day and night", "B678/S345678", 1<<6 | 1<<7 | 1<<8, 1<<3 | 1<<4 | 1<<5 | 1<<6 | 1<<7 | 1<<8},
+		{"lowercase", "b3/s23", 1 << 3, 1<<2 | 1<<3},
+		{"whitespace around clauses", " B3 / S23 ", 1 << 3, 1<<2 | 1<<3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRule(tt.in)
+			if err != nil {
+				t.Fatalf("ParseRule(%q) returned error: %v", tt.in, err)
+			}
+			if got.birth != tt.wantBirth || got.survive != tt.wantSurvive {
+				t.Errorf("ParseRule(%q) = %+v, want birth=%b survive=%b", tt.in, got, tt.wantBirth, tt.wantSurvive)
+			}
+		})
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	tests := []string{
+		"B3S23",  // missing slash
+		"3/S23",  // birth clause missing B prefix
+		"B3/23",  // survive clause missing S prefix
+		"B9/S23", // neighbor count out of range
+		"B3/Sx",  // non-digit neighbor count
+		"",       // empty string
+	}
+
+	for _, in := range tests {
+		t.Run(in, func(t *testing.T) {
+			if _, err := ParseRule(in); err == nil {
+				t.Errorf("ParseRule(%q) = nil error, want error", in)
+			}
+		})
+	}
+}
+
+func TestRuleString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"B3/S23", "B3/S23"},
+		{"B2/S", "B2/S"},
+		{"B36/S23", "B36/S23"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			r, err := ParseRule(tt.in)
+			if err != nil {
+				t.Fatalf("ParseRule(%q) returned error: %v", tt.in, err)
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("Rule.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleBornSurvives(t *testing.T) {
+	r, err := ParseRule("B3/S23")
+	if err != nil {
+		t.Fatalf("ParseRule returned error: %v", err)
+	}
+
+	for n := 0; n <= 8; n++ {
+		wantBorn := n == 3
+		if got := r.born(n); got != wantBorn {
+			t.Errorf("born(%d) = %v, want %v", n, got, wantBorn)
+		}
+
+		wantSurvive := n == 2 || n == 3
+		if got := r.survives(n); got != wantSurvive {
+			t.Errorf("survives(%d) = %v, want %v", n, got, wantSurvive)
+		}
+	}
+}